@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"fmt"
+	"net"
+)
+
+const arpEtherType = 0x0806
+
+// decodeARPSender pulls the sender hardware and protocol addresses out of an IPv4-over-Ethernet
+// ARP frame (Ethernet header included), which is all host discovery needs from it.
+func decodeARPSender(frame []byte) (string, string, error) {
+	const ethernetHeaderLen = 14
+	const arpIPv4HeaderLen = 28 // HTYPE+PTYPE+HLEN+PLEN+OPER(8) + SHA(6)+SPA(4)+THA(6)+TPA(4)
+	if len(frame) < ethernetHeaderLen+arpIPv4HeaderLen {
+		return "", "", fmt.Errorf("arp: frame too short: %d bytes", len(frame))
+	}
+
+	arp := frame[ethernetHeaderLen:]
+	hlen, plen := arp[4], arp[5]
+	if hlen != 6 || plen != 4 {
+		return "", "", fmt.Errorf("arp: unsupported hardware/protocol address length %d/%d", hlen, plen)
+	}
+
+	sha := net.HardwareAddr(arp[8:14])
+	spa := net.IP(arp[14:18])
+	return sha.String(), spa.String(), nil
+}
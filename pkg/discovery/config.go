@@ -23,6 +23,23 @@ type Config struct {
 	PipelineValidationFrequency int64 `mapstructure:"pipelineValidationFrequency" yaml:"pipelineValidationFrequency"`
 	PortRediscoveryFrequency    int64 `mapstructure:"portRediscoveryFrequency" yaml:"portRediscoveryFrequency"`
 	LinkPruneFrequency          int64 `mapstructure:"linkPruneFrequency" yaml:"linkPruneFrequency"`
+
+	DialoutHeartbeatFrequency int64              `mapstructure:"dialoutHeartbeatFrequency" yaml:"dialoutHeartbeatFrequency"`
+	DialoutCollectors         []DialoutCollector `mapstructure:"dialoutCollectors" yaml:"dialoutCollectors"`
+
+	Peers        []string `mapstructure:"peers" yaml:"peers"`
+	PeerInsecure bool     `mapstructure:"peerInsecure" yaml:"peerInsecure"`
+	PeerCertPath string   `mapstructure:"peerCertPath" yaml:"peerCertPath"`
+	PeerKeyPath  string   `mapstructure:"peerKeyPath" yaml:"peerKeyPath"`
+
+	// DiagnosticPort is the port for the introspection HTTP server; 0 (the default) disables it.
+	DiagnosticPort int64 `mapstructure:"diagnosticPort" yaml:"diagnosticPort"`
+
+	// EmitFrequencyMin/EmitFrequencyMax bound the per-port adaptive LLDP emit interval; leaving
+	// both equal to EmitFrequency preserves today's fixed-rate behavior.
+	EmitFrequencyMin   int64 `mapstructure:"emitFrequencyMin" yaml:"emitFrequencyMin"`
+	EmitFrequencyMax   int64 `mapstructure:"emitFrequencyMax" yaml:"emitFrequencyMax"`
+	StabilityThreshold int64 `mapstructure:"stabilityThreshold" yaml:"stabilityThreshold"`
 }
 
 type configWrapper struct {
@@ -37,6 +54,10 @@ func loadConfig() *Config {
 			PipelineValidationFrequency: 60,
 			PortRediscoveryFrequency:    60,
 			LinkPruneFrequency:          2,
+			DialoutHeartbeatFrequency:   10,
+			EmitFrequencyMin:            5,
+			EmitFrequencyMax:            5,
+			StabilityThreshold:          5,
 		},
 	}
 
@@ -77,10 +98,40 @@ func createConfigRoot(agentID string, config *Config) *configtree.Node {
 		&gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: config.PortRediscoveryFrequency}})
 	root.AddPath("config/linkPruneFrequency",
 		&gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: config.LinkPruneFrequency}})
+	root.AddPath("config/dialoutHeartbeatFrequency",
+		&gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: config.DialoutHeartbeatFrequency}})
+	root.AddPath("config/emitFrequencyMin",
+		&gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: config.EmitFrequencyMin}})
+	root.AddPath("config/emitFrequencyMax",
+		&gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: config.EmitFrequencyMax}})
+	root.AddPath("config/stabilityThreshold",
+		&gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: config.StabilityThreshold}})
+	for _, collector := range config.DialoutCollectors {
+		addDialoutCollectorToTree(root, collector)
+	}
+	for _, peer := range config.Peers {
+		root.AddPath(fmt.Sprintf("config/peer[address=%s]/enabled", peer),
+			&gnmi.TypedValue{Value: &gnmi.TypedValue_BoolVal{BoolVal: true}})
+	}
 	root.Add("state/links", nil, nil)
 	return root
 }
 
+// addDialoutCollectorToTree reflects a single dial-out collector's configuration under config/dialout/...
+// The collector list itself is still authored via the YAML config file, the same as other slice-shaped
+// settings; this mirroring exists so operators can see what is currently in effect via gNMI.
+func addDialoutCollectorToTree(root *configtree.Node, collector DialoutCollector) {
+	base := fmt.Sprintf("config/dialout[collector=%s]", collector.Name)
+	root.AddPath(base+"/address",
+		&gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: collector.Address}})
+	root.AddPath(base+"/insecure",
+		&gnmi.TypedValue{Value: &gnmi.TypedValue_BoolVal{BoolVal: collector.Insecure}})
+	root.AddPath(base+"/links",
+		&gnmi.TypedValue{Value: &gnmi.TypedValue_BoolVal{BoolVal: collector.Links}})
+	root.AddPath(base+"/hosts",
+		&gnmi.TypedValue{Value: &gnmi.TypedValue_BoolVal{BoolVal: collector.Hosts}})
+}
+
 // UpdateConfig should be called after the configuration tree has been updated to save the configuration and
 // to reflect it back to the controller's Config structure for easy access.
 func (c *Controller) UpdateConfig() {
@@ -90,6 +141,10 @@ func (c *Controller) UpdateConfig() {
 	c.config.PipelineValidationFrequency = root.GetPath("config/pipelineValidationFrequency").Value().GetIntVal()
 	c.config.PortRediscoveryFrequency = root.GetPath("config/portRediscoveryFrequency").Value().GetIntVal()
 	c.config.LinkPruneFrequency = root.GetPath("config/linkPruneFrequency").Value().GetIntVal()
+	c.config.DialoutHeartbeatFrequency = root.GetPath("config/dialoutHeartbeatFrequency").Value().GetIntVal()
+	c.config.EmitFrequencyMin = root.GetPath("config/emitFrequencyMin").Value().GetIntVal()
+	c.config.EmitFrequencyMax = root.GetPath("config/emitFrequencyMax").Value().GetIntVal()
+	c.config.StabilityThreshold = root.GetPath("config/stabilityThreshold").Value().GetIntVal()
 	saveConfig(c.config)
 	c.setStateIf(Configured, Reconfigured)
 }
@@ -99,42 +154,121 @@ func (c *Controller) RefreshConfig() {
 	// no-op here
 }
 
-func (c *Controller) addLinkToTree(ingressPort uint32, egressPort uint32, egressDeviceID string) {
-	portPath := fmt.Sprintf("state/link[port=%d]/egress-port", ingressPort)
-	portVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: int64(egressPort)}}
-	devicePath := fmt.Sprintf("state/link[port=%d]/egress-device", ingressPort)
-	deviceVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: egressDeviceID}}
-	createTimePath := fmt.Sprintf("state/link[port=%d]/create-time", ingressPort)
+// linkNeighborPath returns the config-tree path for a single (ingress port, neighbor) link entry.
+func linkNeighborPath(ingressPort uint32, neighbor string) string {
+	return fmt.Sprintf("state/link[port=%d][neighbor=%s]", ingressPort, neighbor)
+}
+
+func (c *Controller) addLinkToTree(link *Link) {
+	base := linkNeighborPath(link.IngressPort, neighborKey(link.EgressDeviceID, link.EgressPort))
+	egressPortPath := base + "/egress-port"
+	egressPortVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: int64(link.EgressPort)}}
+	egressDevicePath := base + "/egress-device"
+	egressDeviceVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: link.EgressDeviceID}}
+	createTimePath := base + "/create-time"
 	createTimeVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: uint64(time.Now().UnixNano())}}
+	systemNamePath := base + "/system-name"
+	systemNameVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: link.SystemName}}
+	systemDescriptionPath := base + "/system-description"
+	systemDescriptionVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: link.SystemDescription}}
+	chassisIDSubtypePath := base + "/chassis-id-subtype"
+	chassisIDSubtypeVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: int64(link.ChassisIDSubtype)}}
+	portDescriptionPath := base + "/port-description"
+	portDescriptionVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: link.PortDescription}}
+	managementAddressPath := base + "/management-address"
+	managementAddressVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: link.ManagementAddress}}
+	vlanIDPath := base + "/vlan-id"
+	vlanIDVal := &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: int64(link.VLANID)}}
 
-	c.Root().AddPath(portPath, portVal)
-	c.Root().AddPath(devicePath, deviceVal)
+	c.Root().AddPath(egressPortPath, egressPortVal)
+	c.Root().AddPath(egressDevicePath, egressDeviceVal)
 	c.Root().AddPath(createTimePath, createTimeVal)
+	c.Root().AddPath(systemNamePath, systemNameVal)
+	c.Root().AddPath(systemDescriptionPath, systemDescriptionVal)
+	c.Root().AddPath(chassisIDSubtypePath, chassisIDSubtypeVal)
+	c.Root().AddPath(portDescriptionPath, portDescriptionVal)
+	c.Root().AddPath(managementAddressPath, managementAddressVal)
+	c.Root().AddPath(vlanIDPath, vlanIDVal)
 
-	// Forward the add notification to any subscribe responders
-	c.SendToAllResponders(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{
-		Update: &gnmi.Notification{
-			Timestamp: time.Now().UnixNano(),
-			Update: []*gnmi.Update{
-				{Path: gnmiutils.ToPath(portPath), Val: portVal},
-				{Path: gnmiutils.ToPath(devicePath), Val: deviceVal},
-				{Path: gnmiutils.ToPath(createTimePath), Val: createTimeVal},
-			},
+	notification := &gnmi.Notification{
+		Timestamp: time.Now().UnixNano(),
+		Update: []*gnmi.Update{
+			{Path: gnmiutils.ToPath(egressPortPath), Val: egressPortVal},
+			{Path: gnmiutils.ToPath(egressDevicePath), Val: egressDeviceVal},
+			{Path: gnmiutils.ToPath(createTimePath), Val: createTimeVal},
+			{Path: gnmiutils.ToPath(systemNamePath), Val: systemNameVal},
+			{Path: gnmiutils.ToPath(systemDescriptionPath), Val: systemDescriptionVal},
+			{Path: gnmiutils.ToPath(chassisIDSubtypePath), Val: chassisIDSubtypeVal},
+			{Path: gnmiutils.ToPath(portDescriptionPath), Val: portDescriptionVal},
+			{Path: gnmiutils.ToPath(managementAddressPath), Val: managementAddressVal},
+			{Path: gnmiutils.ToPath(vlanIDPath), Val: vlanIDVal},
 		},
-	}})
+	}
+
+	// Forward the add notification to any subscribe responders and dial-out collectors
+	c.SendToAllResponders(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: notification}})
+	c.broadcastDialout(dialoutLinks, notification)
+	c.metrics.linksAdded.Inc()
+}
+
+// addLinkVerifiedToTree records whether the peer mesh was able to confirm the reverse side of a
+// link, and pushes the result to subscribers the same way an ordinary link update would be.
+func (c *Controller) addLinkVerifiedToTree(ingressPort uint32, neighbor string, verified bool) {
+	path := linkNeighborPath(ingressPort, neighbor) + "/verified"
+	val := &gnmi.TypedValue{Value: &gnmi.TypedValue_BoolVal{BoolVal: verified}}
+	c.Root().AddPath(path, val)
+
+	notification := &gnmi.Notification{
+		Timestamp: time.Now().UnixNano(),
+		Update:    []*gnmi.Update{{Path: gnmiutils.ToPath(path), Val: val}},
+	}
+	c.SendToAllResponders(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: notification}})
+	c.broadcastDialout(dialoutLinks, notification)
 }
 
-func (c *Controller) removeLinkFromTree(ingressPort uint32) {
-	path := fmt.Sprintf("state/link[port=%d]", ingressPort)
+// addEmitIntervalToTree records the current effective LLDP emit interval for a port, in seconds,
+// for observability of the adaptive emit scheduler. The scheduler operates per ingress port, but
+// the config tree has no standalone per-port link node, so the interval is fanned out under every
+// neighbor currently known on that port, consistent with the rest of the state/link[port=N]
+// [neighbor=D/P]/... schema established for the other per-link fields.
+func (c *Controller) addEmitIntervalToTree(ingressPort uint32, interval time.Duration) {
+	c.lock.RLock()
+	neighbors := make([]string, 0, len(c.links[ingressPort]))
+	for neighbor := range c.links[ingressPort] {
+		neighbors = append(neighbors, neighbor)
+	}
+	c.lock.RUnlock()
+
+	if len(neighbors) == 0 {
+		return
+	}
+
+	val := &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: int64(interval / time.Second)}}
+	updates := make([]*gnmi.Update, 0, len(neighbors))
+	for _, neighbor := range neighbors {
+		path := linkNeighborPath(ingressPort, neighbor) + "/emit-interval"
+		c.Root().AddPath(path, val)
+		updates = append(updates, &gnmi.Update{Path: gnmiutils.ToPath(path), Val: val})
+	}
+
+	notification := &gnmi.Notification{Timestamp: time.Now().UnixNano(), Update: updates}
+	c.SendToAllResponders(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: notification}})
+	c.broadcastDialout(dialoutLinks, notification)
+}
+
+func (c *Controller) removeLinkFromTree(ingressPort uint32, neighbor string) {
+	path := linkNeighborPath(ingressPort, neighbor)
 	_ = c.Root().DeletePath(path)
 
-	// Forward the delete notification to any subscribe responders
-	c.SendToAllResponders(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{
-		Update: &gnmi.Notification{
-			Timestamp: time.Now().UnixNano(),
-			Delete:    []*gnmi.Path{gnmiutils.ToPath(path)},
-		},
-	}})
+	notification := &gnmi.Notification{
+		Timestamp: time.Now().UnixNano(),
+		Delete:    []*gnmi.Path{gnmiutils.ToPath(path)},
+	}
+
+	// Forward the delete notification to any subscribe responders and dial-out collectors
+	c.SendToAllResponders(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: notification}})
+	c.broadcastDialout(dialoutLinks, notification)
+	c.metrics.linksRemoved.Inc()
 }
 
 func (c *Controller) addHostToTree(macString string, ipString string, port uint32) {
@@ -149,28 +283,32 @@ func (c *Controller) addHostToTree(macString string, ipString string, port uint3
 	c.Root().AddPath(ipPath, ipVal)
 	c.Root().AddPath(createTimePath, createTimeVal)
 
-	// Forward the add notification to any subscribe responders
-	c.SendToAllResponders(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{
-		Update: &gnmi.Notification{
-			Timestamp: time.Now().UnixNano(),
-			Update: []*gnmi.Update{
-				{Path: gnmiutils.ToPath(portPath), Val: portVal},
-				{Path: gnmiutils.ToPath(ipPath), Val: ipVal},
-				{Path: gnmiutils.ToPath(createTimePath), Val: createTimeVal},
-			},
+	notification := &gnmi.Notification{
+		Timestamp: time.Now().UnixNano(),
+		Update: []*gnmi.Update{
+			{Path: gnmiutils.ToPath(portPath), Val: portVal},
+			{Path: gnmiutils.ToPath(ipPath), Val: ipVal},
+			{Path: gnmiutils.ToPath(createTimePath), Val: createTimeVal},
 		},
-	}})
+	}
+
+	// Forward the add notification to any subscribe responders and dial-out collectors
+	c.SendToAllResponders(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: notification}})
+	c.broadcastDialout(dialoutHosts, notification)
+	c.metrics.hostsAdded.Inc()
 }
 
 func (c *Controller) removeHostFromTree(macString string) {
 	path := fmt.Sprintf("state/host[mac=%s]", macString)
 	_ = c.Root().DeletePath(path)
 
-	// Forward the delete notification to any subscribe responders
-	c.SendToAllResponders(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{
-		Update: &gnmi.Notification{
-			Timestamp: time.Now().UnixNano(),
-			Delete:    []*gnmi.Path{gnmiutils.ToPath(path)},
-		},
-	}})
+	notification := &gnmi.Notification{
+		Timestamp: time.Now().UnixNano(),
+		Delete:    []*gnmi.Path{gnmiutils.ToPath(path)},
+	}
+
+	// Forward the delete notification to any subscribe responders and dial-out collectors
+	c.SendToAllResponders(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: notification}})
+	c.broadcastDialout(dialoutHosts, notification)
+	c.metrics.hostsRemoved.Inc()
 }
@@ -7,6 +7,7 @@ package discovery
 
 import (
 	"context"
+	"fmt"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-net-lib/pkg/configtree"
 	"github.com/onosproject/onos-net-lib/pkg/p4utils"
@@ -55,7 +56,7 @@ type Controller struct {
 	lock   sync.RWMutex
 	config *Config
 	ports  map[string]*Port
-	links  map[uint32]*Link
+	links  map[uint32]map[string]*Link
 	hosts  map[string]*Host
 
 	conn       *grpc.ClientConn
@@ -74,6 +75,23 @@ type Controller struct {
 	role       *p4api.Role
 
 	monitor *portMonitor
+
+	dialoutLock sync.RWMutex
+	dialoutSubs map[string]*dialoutSub
+
+	peerLock   sync.RWMutex
+	peerTables map[string]map[uint32]map[string]*peerLink
+
+	metrics *agentMetrics
+
+	diagLock     sync.RWMutex
+	stateHistory []stateTransition
+	portStats    map[uint32]*portPacketStats
+	hostStats    map[string]*hostPacketStats
+	streamSince  time.Time
+
+	emitLock   sync.Mutex
+	emitStates map[uint32]*portEmitState
 }
 
 // Port holds data about each discovered switch ports
@@ -84,12 +102,41 @@ type Port struct {
 	LastChange uint64
 }
 
-// Link holds data about each discovered ingress links
+// Link holds data about each discovered ingress link. A single ingress port may legitimately hear
+// LLDP from more than one neighbor (shared media, misconfiguration, or a passive tap), so links
+// are keyed by ingress port *and* neighbor, not by ingress port alone.
 type Link struct {
 	EgressPort     uint32
 	EgressDeviceID string
 	IngressPort    uint32
 	LastUpdate     time.Time
+
+	// Additional LLDP TLVs carried by the frame that created/refreshed this link
+	SystemName        string
+	SystemDescription string
+	ChassisIDSubtype  uint8
+	PortDescription   string
+	ManagementAddress string
+	VLANID            uint32
+}
+
+// NeighborInfo carries the fields extracted from a single received LLDP frame, as parsed by the
+// packet-in handler.
+type NeighborInfo struct {
+	EgressDeviceID    string
+	EgressPort        uint32
+	SystemName        string
+	SystemDescription string
+	ChassisIDSubtype  uint8
+	PortDescription   string
+	ManagementAddress string
+	VLANID            uint32
+}
+
+// neighborKey identifies a neighbor on a given ingress port by its egress device and port, so that
+// two distinct neighbors observed on the same ingress port don't overwrite one another.
+func neighborKey(egressDeviceID string, egressPort uint32) string {
+	return fmt.Sprintf("%s/%d", egressDeviceID, egressPort)
 }
 
 // Host is a simple representation of a host network interface discovered by the ONOS lite
@@ -109,8 +156,14 @@ func NewController(targetAddress string, agentID string) *Controller {
 		IngressDeviceID:  agentID,
 		config:           config,
 		ports:            make(map[string]*Port),
-		links:            make(map[uint32]*Link),
+		links:            make(map[uint32]map[string]*Link),
 		monitor:          &portMonitor{},
+		dialoutSubs:      make(map[string]*dialoutSub),
+		peerTables:       make(map[string]map[uint32]map[string]*peerLink),
+		metrics:          newAgentMetrics(agentID),
+		portStats:        make(map[uint32]*portPacketStats),
+		hostStats:        make(map[string]*hostPacketStats),
+		emitStates:       make(map[uint32]*portEmitState),
 	}
 	ctrl.GNMIConfigurable.Configurable = ctrl
 	return ctrl
@@ -131,55 +184,95 @@ func (c *Controller) Stop() {
 	}
 }
 
-// GetLinks returns a list of currently discovered links, sorted by ingress port
+// GetLinks returns a list of currently discovered links, sorted by ingress port and neighbor
 func (c *Controller) GetLinks() []*Link {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
 
 	links := make([]*Link, 0, len(c.links))
-	for _, link := range c.links {
-		links = append(links, link)
+	for _, neighbors := range c.links {
+		for _, link := range neighbors {
+			links = append(links, link)
+		}
 	}
 
-	sort.SliceStable(links, func(i, j int) bool { return links[i].IngressPort < links[j].IngressPort })
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].IngressPort != links[j].IngressPort {
+			return links[i].IngressPort < links[j].IngressPort
+		}
+		return neighborKey(links[i].EgressDeviceID, links[i].EgressPort) <
+			neighborKey(links[j].EgressDeviceID, links[j].EgressPort)
+	})
 	return links
 }
 
-func (c *Controller) updateIngressLink(ingressPort uint32, egressPort uint32, egressDeviceID string) {
+// updateIngressLink records (or refreshes) a link learned from an LLDP frame received on
+// ingressPort, keyed by both the ingress port and the reporting neighbor so that multiple
+// neighbors on the same port don't overwrite one another.
+func (c *Controller) updateIngressLink(ingressPort uint32, info NeighborInfo) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	link, ok := c.links[ingressPort]
-	if !ok || link.EgressPort != egressPort || link.EgressDeviceID != egressDeviceID {
+
+	neighbors, ok := c.links[ingressPort]
+	if !ok {
+		neighbors = make(map[string]*Link)
+		c.links[ingressPort] = neighbors
+	}
+
+	key := neighborKey(info.EgressDeviceID, info.EgressPort)
+	link, ok := neighbors[key]
+	if !ok {
 		link = &Link{
-			EgressPort:     egressPort,
-			EgressDeviceID: egressDeviceID,
+			EgressPort:     info.EgressPort,
+			EgressDeviceID: info.EgressDeviceID,
 			IngressPort:    ingressPort,
 		}
+	}
+
+	link.SystemName = info.SystemName
+	link.SystemDescription = info.SystemDescription
+	link.ChassisIDSubtype = info.ChassisIDSubtype
+	link.PortDescription = info.PortDescription
+	link.ManagementAddress = info.ManagementAddress
+	link.VLANID = info.VLANID
+	link.LastUpdate = time.Now()
+
+	if !ok {
+		neighbors[key] = link
 
 		// Add the link to our internal structure and to the config tree
-		c.links[ingressPort] = link
-		log.Infof("Added a new link: %d <- %s/%d", ingressPort, egressDeviceID, egressPort)
-		c.addLinkToTree(ingressPort, egressPort, egressDeviceID)
+		log.Infof("Added a new link: %d <- %s/%d", ingressPort, info.EgressDeviceID, info.EgressPort)
+		if len(neighbors) > 1 {
+			log.Infof("Port %d now hears LLDP from %d neighbors", ingressPort, len(neighbors))
+		}
+		c.addLinkToTree(link)
 	}
-	link.LastUpdate = time.Now()
+
+	go c.verifyLinkAgainstMesh(ingressPort, info.EgressPort, info.EgressDeviceID)
 }
 
 func (c *Controller) pruneLinks() {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	limit := time.Now().Add(-30 * time.Second)
-	for ingressPort, link := range c.links {
-		if link.LastUpdate.Before(limit) {
-			c.deleteLink(ingressPort)
-			log.Infof("Pruned stale link: %d <- %s/%d", link.IngressPort, link.EgressDeviceID, link.EgressPort)
+	for ingressPort, neighbors := range c.links {
+		for key, link := range neighbors {
+			if link.LastUpdate.Before(limit) {
+				c.deleteLink(ingressPort, key)
+				c.metrics.linksPruned.Inc()
+				log.Infof("Pruned stale link: %d <- %s/%d", link.IngressPort, link.EgressDeviceID, link.EgressPort)
+			}
 		}
 	}
 }
 
-func (c *Controller) deleteLink(ingressPort uint32) {
+func (c *Controller) deleteLink(ingressPort uint32, key string) {
 	// Delete the link from our internal structure and from the config tree
-	delete(c.links, ingressPort)
-	c.removeLinkFromTree(ingressPort)
+	delete(c.links[ingressPort], key)
+	if len(c.links[ingressPort]) == 0 {
+		delete(c.links, ingressPort)
+	}
+	c.removeLinkFromTree(ingressPort, key)
 }
 
 func (c *Controller) deleteHost(macString string) {
@@ -201,6 +294,7 @@ func (c *Controller) setState(state State) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	c.state = state
+	c.recordStateTransition(state)
 }
 
 // Change state to the new state, but only if in the given condition state
@@ -209,6 +303,7 @@ func (c *Controller) setStateIf(condition State, state State) {
 	defer c.lock.Unlock()
 	if c.state == condition {
 		c.state = state
+		c.recordStateTransition(state)
 	}
 }
 
@@ -236,6 +331,7 @@ func (c *Controller) pruneHosts() {
 	for mac, host := range c.hosts {
 		if host.LastUpdate.Before(limit) {
 			c.deleteHost(mac)
+			c.metrics.hostsPruned.Inc()
 			log.Infof("Pruned stale host: %s <- %s/%s", host.MAC, host.IP, host.Port)
 		}
 	}
@@ -278,24 +374,58 @@ func (c *Controller) setupForDiscovery() {
 
 	// Setup packet-in handler
 	go c.handlePackets()
+
+	// Start dial-out publishing to any configured collectors
+	c.startDialout()
+
+	// Start the peer verification mesh, if any peers were configured
+	c.startPeerMesh()
+
+	// Start the diagnostic/introspection HTTP server, if configured
+	c.startDiagnosticServer()
+
+	// Watch for interface state changes so ports are re-discovered as soon as they flap, rather
+	// than waiting on the PortRediscoveryFrequency ticker in enterDiscovery. Started here, once,
+	// rather than from enterDiscovery, since that re-runs on every reconfiguration and would
+	// otherwise leak one subscription goroutine per UpdateConfig call.
+	c.startPortMonitor()
+
+	// Start the per-port adaptive LLDP emit scheduler, if enabled. Also started here, once, for
+	// the same reason as the port monitor above: enterDiscovery re-runs on every reconfiguration
+	// and would otherwise leak one scheduler goroutine per UpdateConfig call.
+	if c.adaptiveEmitEnabled() {
+		c.startAdaptiveEmitScheduler()
+	}
 }
 
 func (c *Controller) enterDiscovery() {
-	tLinks := time.NewTicker(time.Duration(c.config.EmitFrequency) * time.Second)
 	tConf := time.NewTicker(time.Duration(c.config.PipelineValidationFrequency) * time.Second)
 	tPorts := time.NewTicker(time.Duration(c.config.PortRediscoveryFrequency) * time.Second)
 	tPrune := time.NewTicker(time.Duration(c.config.LinkPruneFrequency) * time.Second)
 
+	// Emit LLDP either on a single fixed-rate ticker (today's behavior, kept when
+	// EmitFrequencyMin == EmitFrequencyMax) or via the per-port adaptive scheduler started once
+	// from setupForDiscovery.
+	var linksC <-chan time.Time
+	if !c.adaptiveEmitEnabled() {
+		tLinks := time.NewTicker(time.Duration(c.config.EmitFrequency) * time.Second)
+		defer tLinks.Stop()
+		linksC = tLinks.C
+	}
+
 	// Do I have to emit ARP packets here? I guess so...
 	for c.getState() == Configured {
 		select {
 		// Periodically emit LLDP packets
-		case <-tLinks.C:
+		case <-linksC:
 			c.emitLLDPPackets()
 
-		// Periodically re-discover ports
+		// Periodically re-discover ports; skipped while the event-driven port monitor is active,
+		// retained as a degraded-mode fallback for targets that do not support ON_CHANGE
 		case <-tPorts.C:
-			c.discoverPorts()
+			if !c.monitor.supportsOnChange() {
+				c.discoverPorts()
+			}
 
 		// Periodically validate pipeline config
 		case <-tConf.C:
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const maxStateHistory = 64
+
+// stateTransition records one State change along with when it happened, for the /state endpoint.
+type stateTransition struct {
+	State State     `json:"state"`
+	Time  time.Time `json:"time"`
+}
+
+// portPacketStats tracks LLDP packet counters for a single switch port.
+type portPacketStats struct {
+	LLDPTx       uint64    `json:"lldpTx"`
+	LLDPRx       uint64    `json:"lldpRx"`
+	LastLLDPTime time.Time `json:"lastLldpTime"`
+}
+
+// hostPacketStats tracks ARP packet counters for a single discovered host.
+type hostPacketStats struct {
+	ARPCount    uint64    `json:"arpCount"`
+	LastARPTime time.Time `json:"lastArpTime"`
+}
+
+// startDiagnosticServer brings up the introspection HTTP server, unless DiagnosticPort is unset.
+func (c *Controller) startDiagnosticServer() {
+	if c.config.DiagnosticPort == 0 {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", c.handleDiagState)
+	mux.HandleFunc("/ports", c.handleDiagPorts)
+	mux.HandleFunc("/hosts", c.handleDiagHosts)
+	mux.HandleFunc("/stream", c.handleDiagStream)
+	mux.HandleFunc("/tree", c.handleDiagTree)
+	mux.Handle("/metrics", promhttp.Handler())
+
+	addr := fmt.Sprintf(":%d", c.config.DiagnosticPort)
+	log.Infof("Starting diagnostic server on %s", addr)
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Warnf("Diagnostic server stopped: %+v", err)
+		}
+	}()
+}
+
+func (c *Controller) recordStateTransition(state State) {
+	c.diagLock.Lock()
+	defer c.diagLock.Unlock()
+	c.stateHistory = append(c.stateHistory, stateTransition{State: state, Time: time.Now()})
+	if len(c.stateHistory) > maxStateHistory {
+		c.stateHistory = c.stateHistory[len(c.stateHistory)-maxStateHistory:]
+	}
+}
+
+func (c *Controller) handleDiagState(w http.ResponseWriter, _ *http.Request) {
+	c.diagLock.RLock()
+	history := append([]stateTransition(nil), c.stateHistory...)
+	c.diagLock.RUnlock()
+
+	writeJSON(w, struct {
+		State   State             `json:"state"`
+		History []stateTransition `json:"history"`
+	}{State: c.getState(), History: history})
+}
+
+// RecordLLDPTx and RecordLLDPRx are called from the packet-in/emit paths to maintain per-port
+// LLDP counters for the /ports diagnostic endpoint.
+func (c *Controller) RecordLLDPTx(port uint32) {
+	c.portStat(port).LLDPTx++
+}
+
+func (c *Controller) RecordLLDPRx(port uint32) {
+	stats := c.portStat(port)
+	stats.LLDPRx++
+	stats.LastLLDPTime = time.Now()
+}
+
+func (c *Controller) portStat(port uint32) *portPacketStats {
+	c.diagLock.Lock()
+	defer c.diagLock.Unlock()
+	stats, ok := c.portStats[port]
+	if !ok {
+		stats = &portPacketStats{}
+		c.portStats[port] = stats
+	}
+	return stats
+}
+
+func (c *Controller) handleDiagPorts(w http.ResponseWriter, _ *http.Request) {
+	c.diagLock.RLock()
+	defer c.diagLock.RUnlock()
+	writeJSON(w, c.portStats)
+}
+
+// RecordARP is called from the packet-in path to maintain per-host ARP counters for the /hosts
+// diagnostic endpoint.
+func (c *Controller) RecordARP(macString string) {
+	c.diagLock.Lock()
+	defer c.diagLock.Unlock()
+	stats, ok := c.hostStats[macString]
+	if !ok {
+		stats = &hostPacketStats{}
+		c.hostStats[macString] = stats
+	}
+	stats.ARPCount++
+	stats.LastARPTime = time.Now()
+}
+
+func (c *Controller) handleDiagHosts(w http.ResponseWriter, _ *http.Request) {
+	c.diagLock.RLock()
+	defer c.diagLock.RUnlock()
+	writeJSON(w, c.hostStats)
+}
+
+// NoteStreamEstablished records when the P4Runtime stream channel was (re-)established, so the
+// /stream endpoint can report its up-time.
+func (c *Controller) NoteStreamEstablished() {
+	c.diagLock.Lock()
+	defer c.diagLock.Unlock()
+	c.streamSince = time.Now()
+}
+
+func (c *Controller) handleDiagStream(w http.ResponseWriter, _ *http.Request) {
+	c.diagLock.RLock()
+	since := c.streamSince
+	c.diagLock.RUnlock()
+
+	up := !since.IsZero()
+	var upTime time.Duration
+	if up {
+		upTime = time.Since(since)
+	}
+
+	writeJSON(w, struct {
+		Up           bool   `json:"up"`
+		UpTimeMillis int64  `json:"upTimeMillis"`
+		Cookie       uint64 `json:"cookie"`
+		ElectionID   string `json:"electionId,omitempty"`
+	}{
+		Up:           up,
+		UpTimeMillis: upTime.Milliseconds(),
+		Cookie:       c.cookie,
+		ElectionID:   electionIDString(c.electionID),
+	})
+}
+
+func electionIDString(id *p4api.Uint128) string {
+	if id == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", id.High, id.Low)
+}
+
+// handleDiagTree dumps the raw config tree by issuing a gNMI Get for the root path against our
+// own GNMIConfigurable, the same way any external gNMI client would.
+func (c *Controller) handleDiagTree(w http.ResponseWriter, _ *http.Request) {
+	notifications, err := c.ProcessConfigGet(nil, []*gnmi.Path{{}})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	marshaled, err := protojson.Marshal(&gnmi.GetResponse{Notification: notifications})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(marshaled)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,230 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-net-lib/pkg/gnmiutils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// DialoutCollector describes a single gNMI dial-out destination, its TLS credentials and which
+// subtrees (links, hosts) it wants to receive.
+type DialoutCollector struct {
+	Name     string `mapstructure:"name" yaml:"name"`
+	Address  string `mapstructure:"address" yaml:"address"`
+	CertPath string `mapstructure:"certPath" yaml:"certPath"`
+	KeyPath  string `mapstructure:"keyPath" yaml:"keyPath"`
+	Insecure bool   `mapstructure:"insecure" yaml:"insecure"`
+	Links    bool   `mapstructure:"links" yaml:"links"`
+	Hosts    bool   `mapstructure:"hosts" yaml:"hosts"`
+}
+
+// dialoutKind identifies which subtree a notification being fanned out to collectors belongs to.
+type dialoutKind int
+
+const (
+	dialoutLinks dialoutKind = iota
+	dialoutHosts
+)
+
+const (
+	dialoutMinBackoff = 1 * time.Second
+	dialoutMaxBackoff = 30 * time.Second
+)
+
+// dialoutPublishMethod is the collector-side RPC this dial-out client streams SubscribeResponses
+// to. Unlike the standard gnmi.gNMI/Subscribe RPC (which the target would have to *serve*, not
+// call, to push its own data), this is the inverse shape dial-out needs: the agent is the client
+// and the collector is the server, so the agent pushes SubscribeResponses up the stream it opened.
+// There is no generated stub for this collector-side service, so the stream is opened directly
+// against the gRPC connection with the proto codec handling the framing, the same way generated
+// client stubs do it under the hood.
+const dialoutPublishMethod = "/gnmi.gNMIDialOut/Publish"
+
+var dialoutPublishStreamDesc = &grpc.StreamDesc{
+	StreamName:    "Publish",
+	ClientStreams: true,
+}
+
+// dialoutSub tracks a live outbound Publish stream to a single collector.
+type dialoutSub struct {
+	collector DialoutCollector
+	stream    grpc.ClientStream
+	sendLock  sync.Mutex
+}
+
+// startDialout launches one reconnecting goroutine per configured collector.
+func (c *Controller) startDialout() {
+	for _, collector := range c.config.DialoutCollectors {
+		go c.runDialoutCollector(collector)
+	}
+}
+
+// runDialoutCollector maintains a connection to a single collector for the lifetime of the agent,
+// reconnecting with exponential backoff whenever the stream fails or the collector is unreachable.
+func (c *Controller) runDialoutCollector(collector DialoutCollector) {
+	backoff := dialoutMinBackoff
+	for c.getState() != Stopped {
+		if err := c.dialAndStream(collector); err != nil {
+			log.Warnf("Dial-out to collector %s (%s) failed: %+v", collector.Name, collector.Address, err)
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > dialoutMaxBackoff {
+			backoff = dialoutMaxBackoff
+		}
+	}
+}
+
+func dialoutCredentials(collector DialoutCollector) (grpc.DialOption, error) {
+	return clientTLSCredentials(collector.Insecure, collector.CertPath, collector.KeyPath)
+}
+
+// clientTLSCredentials builds the dial credentials for an outbound gRPC connection, reusing the
+// same cert/key pair (or plaintext) shape used to configure the agent's own gNMI server. Both the
+// dial-out publisher and the peer mesh rely on this to keep their TLS story consistent.
+func clientTLSCredentials(insecureConn bool, certPath string, keyPath string) (grpc.DialOption, error) {
+	if insecureConn || certPath == "" {
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	}
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})), nil
+}
+
+// dialAndStream opens a connection to the collector, registers it to receive live updates and
+// blocks emitting periodic heartbeats until the stream fails or the agent is stopped.
+func (c *Controller) dialAndStream(collector DialoutCollector) error {
+	dialOpt, err := dialoutCredentials(collector)
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.Dial(collector.Address, dialOpt, grpc.WithBlock(), grpc.WithTimeout(dialoutMaxBackoff))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := conn.NewStream(c.ctx, dialoutPublishStreamDesc, dialoutPublishMethod)
+	if err != nil {
+		return err
+	}
+	defer stream.CloseSend()
+
+	sub := &dialoutSub{collector: collector, stream: stream}
+	log.Infof("Dial-out connected to collector %s (%s)", collector.Name, collector.Address)
+
+	c.sendDialoutSnapshot(sub)
+	c.registerDialoutSub(sub)
+	defer c.unregisterDialoutSub(collector.Name)
+
+	heartbeat := time.NewTicker(time.Duration(c.config.DialoutHeartbeatFrequency) * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if err := c.sendDialoutHeartbeat(sub); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendDialoutSnapshot pushes the egress port and egress device of every current link as an initial
+// sync, if the collector opted into links. Hosts are not part of the snapshot; a collector receives
+// host state only from the live updates that follow.
+func (c *Controller) sendDialoutSnapshot(sub *dialoutSub) {
+	now := time.Now().UnixNano()
+
+	if sub.collector.Links {
+		updates := make([]*gnmi.Update, 0, len(c.GetLinks()))
+		for _, link := range c.GetLinks() {
+			base := linkNeighborPath(link.IngressPort, neighborKey(link.EgressDeviceID, link.EgressPort))
+			updates = append(updates,
+				&gnmi.Update{Path: gnmiutils.ToPath(base + "/egress-port"), Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: int64(link.EgressPort)}}},
+				&gnmi.Update{Path: gnmiutils.ToPath(base + "/egress-device"), Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: link.EgressDeviceID}}},
+			)
+		}
+		if len(updates) > 0 {
+			c.sendDialout(sub, &gnmi.Notification{Timestamp: now, Update: updates})
+		}
+	}
+}
+
+// sendDialoutHeartbeat emits a keepalive notification so the collector can detect a silent agent,
+// and records the exchange under state/dialout/<collector>/last-ack for observability.
+func (c *Controller) sendDialoutHeartbeat(sub *dialoutSub) error {
+	now := time.Now().UnixNano()
+	path := fmt.Sprintf("state/dialout/%s/last-ack", sub.collector.Name)
+	val := &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: uint64(now)}}
+
+	if err := c.sendDialout(sub, &gnmi.Notification{
+		Timestamp: now,
+		Update:    []*gnmi.Update{{Path: gnmiutils.ToPath(path), Val: val}},
+	}); err != nil {
+		return err
+	}
+
+	c.Root().AddPath(path, val)
+	return nil
+}
+
+func (c *Controller) sendDialout(sub *dialoutSub, notification *gnmi.Notification) error {
+	sub.sendLock.Lock()
+	defer sub.sendLock.Unlock()
+	return sub.stream.SendMsg(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: notification}})
+}
+
+func (c *Controller) registerDialoutSub(sub *dialoutSub) {
+	c.dialoutLock.Lock()
+	defer c.dialoutLock.Unlock()
+	c.dialoutSubs[sub.collector.Name] = sub
+}
+
+func (c *Controller) unregisterDialoutSub(name string) {
+	c.dialoutLock.Lock()
+	defer c.dialoutLock.Unlock()
+	delete(c.dialoutSubs, name)
+}
+
+// broadcastDialout forwards a link/host notification to every currently connected collector that
+// has opted into that subtree.
+func (c *Controller) broadcastDialout(kind dialoutKind, notification *gnmi.Notification) {
+	c.dialoutLock.RLock()
+	subs := make([]*dialoutSub, 0, len(c.dialoutSubs))
+	for _, sub := range c.dialoutSubs {
+		subs = append(subs, sub)
+	}
+	c.dialoutLock.RUnlock()
+
+	for _, sub := range subs {
+		if (kind == dialoutLinks && !sub.collector.Links) || (kind == dialoutHosts && !sub.collector.Hosts) {
+			continue
+		}
+		if err := c.sendDialout(sub, notification); err != nil {
+			log.Warnf("Unable to forward update to dial-out collector %s: %+v", sub.collector.Name, err)
+		}
+	}
+}
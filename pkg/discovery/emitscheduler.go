@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// portEmitState tracks the adaptive LLDP emit schedule for a single port: its current effective
+// interval, how many consecutive checks it has been stable for, and when it is next due to emit.
+type portEmitState struct {
+	interval   time.Duration
+	stableRuns int64
+	neighbors  string
+	nextEmit   time.Time
+}
+
+// adaptiveEmitEnabled reports whether the per-port scheduler should run at all; when min equals
+// max there is nothing to adapt and enterDiscovery keeps using its single fixed-rate ticker.
+func (c *Controller) adaptiveEmitEnabled() bool {
+	return c.config.EmitFrequencyMin != c.config.EmitFrequencyMax
+}
+
+// startAdaptiveEmitScheduler launches the goroutine that periodically checks every known port and
+// emits LLDP on the ones whose backed-off or floored interval has elapsed.
+func (c *Controller) startAdaptiveEmitScheduler() {
+	go c.runAdaptiveEmitScheduler()
+}
+
+func (c *Controller) runAdaptiveEmitScheduler() {
+	tick := time.NewTicker(time.Duration(c.config.EmitFrequencyMin) * time.Second)
+	defer tick.Stop()
+	for c.getState() != Stopped {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-tick.C:
+			c.emitDuePorts()
+		}
+	}
+}
+
+func (c *Controller) emitDuePorts() {
+	c.lock.RLock()
+	ports := make([]*Port, 0, len(c.ports))
+	for _, port := range c.ports {
+		ports = append(ports, port)
+	}
+	c.lock.RUnlock()
+
+	now := time.Now()
+	for _, port := range ports {
+		state := c.emitState(port.Number)
+		if now.Before(state.nextEmit) {
+			continue
+		}
+		c.emitLLDPPacket(port)
+		c.advanceEmitState(port.Number, state)
+	}
+}
+
+func (c *Controller) emitState(port uint32) *portEmitState {
+	c.emitLock.Lock()
+	defer c.emitLock.Unlock()
+	state, ok := c.emitStates[port]
+	if !ok {
+		state = &portEmitState{interval: time.Duration(c.config.EmitFrequencyMin) * time.Second}
+		c.emitStates[port] = state
+	}
+	return state
+}
+
+// advanceEmitState re-evaluates a port's emit interval after an emission: a flap (or no neighbor
+// at all) drops it back to the floor, while StabilityThreshold consecutive unchanged checks back
+// it off towards the ceiling.
+func (c *Controller) advanceEmitState(port uint32, state *portEmitState) {
+	signature := c.neighborSignature(port)
+
+	c.emitLock.Lock()
+	floor := time.Duration(c.config.EmitFrequencyMin) * time.Second
+	ceiling := time.Duration(c.config.EmitFrequencyMax) * time.Second
+
+	if signature == "" || signature != state.neighbors {
+		state.interval = floor
+		state.stableRuns = 0
+	} else {
+		state.stableRuns++
+		if state.stableRuns >= c.config.StabilityThreshold {
+			state.interval *= 2
+			if state.interval > ceiling {
+				state.interval = ceiling
+			}
+			state.stableRuns = 0
+		}
+	}
+	state.neighbors = signature
+	state.nextEmit = time.Now().Add(state.interval)
+	interval := state.interval
+	c.emitLock.Unlock()
+
+	c.addEmitIntervalToTree(port, interval)
+}
+
+// neighborSignature returns a stable, order-independent fingerprint of the neighbors currently
+// observed on a port, used to detect flaps (or the absence of any neighbor) between emit checks.
+func (c *Controller) neighborSignature(ingressPort uint32) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	neighbors := c.links[ingressPort]
+	keys := make([]string, 0, len(neighbors))
+	for key := range neighbors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+const (
+	lldpEtherType = 0x88cc
+
+	lldpTLVTypeEnd               = 0
+	lldpTLVTypeChassisID         = 1
+	lldpTLVTypePortID            = 2
+	lldpTLVTypeTTL               = 3
+	lldpTLVTypePortDescription   = 4
+	lldpTLVTypeSystemName        = 5
+	lldpTLVTypeSystemDescription = 6
+	lldpTLVTypeManagementAddress = 8
+	lldpTLVTypeOrgSpecific       = 127
+
+	// lldpChassisIDSubtypeLocal and lldpPortIDSubtypeLocal mark the chassis/port ID value as a
+	// locally-assigned string, the shape this agent itself emits on the wire.
+	lldpChassisIDSubtypeLocal = 7
+	lldpPortIDSubtypeLocal    = 7
+
+	// ieee8021SubtypeVLANID is the IEEE 802.1 organizationally-specific TLV subtype carrying the
+	// port VLAN ID, under OUI 00-80-c2.
+	ieee8021SubtypeVLANID = 1
+)
+
+// ieee8021OUI identifies the IEEE 802.1 organization for organizationally-specific TLVs.
+var ieee8021OUI = [3]byte{0x00, 0x80, 0xc2}
+
+// decodeLLDPNeighborInfo parses the TLVs of a received LLDP frame (Ethernet header included) into
+// a NeighborInfo. The chassis ID TLV's value is taken as the neighbor's device ID and the port ID
+// TLV's value as its egress port number; both are required, everything else is best-effort.
+func decodeLLDPNeighborInfo(frame []byte) (NeighborInfo, error) {
+	const ethernetHeaderLen = 14
+	if len(frame) < ethernetHeaderLen {
+		return NeighborInfo{}, fmt.Errorf("lldp: frame too short: %d bytes", len(frame))
+	}
+	if etherType := binary.BigEndian.Uint16(frame[12:14]); etherType != lldpEtherType {
+		return NeighborInfo{}, fmt.Errorf("lldp: unexpected ethertype 0x%04x", etherType)
+	}
+
+	var info NeighborInfo
+	var haveChassisID, havePortID bool
+
+	tlvs := frame[ethernetHeaderLen:]
+	for len(tlvs) >= 2 {
+		header := binary.BigEndian.Uint16(tlvs[0:2])
+		tlvType := int(header >> 9)
+		tlvLen := int(header & 0x01ff)
+		tlvs = tlvs[2:]
+		if tlvLen > len(tlvs) {
+			return NeighborInfo{}, fmt.Errorf("lldp: truncated TLV of type %d", tlvType)
+		}
+		value := tlvs[:tlvLen]
+		tlvs = tlvs[tlvLen:]
+
+		switch tlvType {
+		case lldpTLVTypeEnd:
+			tlvs = nil
+		case lldpTLVTypeChassisID:
+			if len(value) < 2 {
+				return NeighborInfo{}, fmt.Errorf("lldp: short chassis ID TLV")
+			}
+			info.ChassisIDSubtype = value[0]
+			info.EgressDeviceID = string(value[1:])
+			haveChassisID = true
+		case lldpTLVTypePortID:
+			if len(value) < 2 {
+				return NeighborInfo{}, fmt.Errorf("lldp: short port ID TLV")
+			}
+			port, err := parsePortIDValue(value[1:])
+			if err != nil {
+				return NeighborInfo{}, err
+			}
+			info.EgressPort = port
+			havePortID = true
+		case lldpTLVTypePortDescription:
+			info.PortDescription = string(value)
+		case lldpTLVTypeSystemName:
+			info.SystemName = string(value)
+		case lldpTLVTypeSystemDescription:
+			info.SystemDescription = string(value)
+		case lldpTLVTypeManagementAddress:
+			info.ManagementAddress = parseManagementAddressValue(value)
+		case lldpTLVTypeOrgSpecific:
+			if vlanID, ok := parseIEEE8021VLANID(value); ok {
+				info.VLANID = vlanID
+			}
+		}
+	}
+
+	if !haveChassisID || !havePortID {
+		return NeighborInfo{}, fmt.Errorf("lldp: missing chassis ID or port ID TLV")
+	}
+	return info, nil
+}
+
+// parseIEEE8021VLANID extracts the port VLAN ID from an IEEE 802.1 organizationally-specific TLV
+// value (OUI 00-80-c2, subtype 1), ignoring any other organization or subtype.
+func parseIEEE8021VLANID(value []byte) (uint32, bool) {
+	if len(value) < 6 {
+		return 0, false
+	}
+	if [3]byte(value[0:3]) != ieee8021OUI || value[3] != ieee8021SubtypeVLANID {
+		return 0, false
+	}
+	return uint32(binary.BigEndian.Uint16(value[4:6])), true
+}
+
+// parsePortIDValue decodes a port ID TLV value carrying either a locally-assigned numeric string
+// (the common case for frames this agent itself emits) or a raw 4-byte port number.
+func parsePortIDValue(value []byte) (uint32, error) {
+	port, err := strconv.ParseUint(string(value), 10, 32)
+	if err == nil {
+		return uint32(port), nil
+	}
+	if len(value) == 4 {
+		return binary.BigEndian.Uint32(value), nil
+	}
+	return 0, fmt.Errorf("lldp: unparseable port ID %q: %w", value, err)
+}
+
+// parseManagementAddressValue pulls the address octets out of a management address TLV, skipping
+// the leading address-string-length and address-subtype bytes; it does not attempt to decode the
+// interface-numbering subtype or OID suffix that follow.
+func parseManagementAddressValue(value []byte) string {
+	if len(value) < 2 {
+		return ""
+	}
+	addrStrLen := int(value[0])
+	if addrStrLen < 1 || 1+addrStrLen > len(value) {
+		return ""
+	}
+	addr := value[2 : 1+addrStrLen]
+	if len(addr) == 4 {
+		return fmt.Sprintf("%d.%d.%d.%d", addr[0], addr[1], addr[2], addr[3])
+	}
+	return fmt.Sprintf("% x", addr)
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// egressPortMetadataID is the PacketMetadata.MetadataId Stratum uses to steer a PacketOut to a
+// specific egress port.
+const egressPortMetadataID = 1
+
+// lldpMulticastAddress is the standard nearest-bridge LLDP destination MAC, 01:80:c2:00:00:0e.
+var lldpMulticastAddress = []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x0e}
+
+// localEthernetAddress is a placeholder source MAC; the pipeline rewrites the source address of
+// packet-outs to the port's real interface address, so the exact value sent here doesn't matter.
+var localEthernetAddress = []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+// emitLLDPPackets sends an LLDP frame advertising this agent's identity out of every known,
+// operationally up port, and records the emission for the /ports diagnostic endpoint.
+func (c *Controller) emitLLDPPackets() {
+	c.lock.RLock()
+	ports := make([]*Port, 0, len(c.ports))
+	for _, port := range c.ports {
+		ports = append(ports, port)
+	}
+	c.lock.RUnlock()
+
+	for _, port := range ports {
+		c.emitLLDPPacket(port)
+	}
+}
+
+// emitLLDPPacket sends a single LLDP frame out of the given port, unless it is operationally down,
+// and records the emission for the /ports diagnostic endpoint. Factored out of emitLLDPPackets so
+// the adaptive emit scheduler can emit on a single port without iterating all of them.
+func (c *Controller) emitLLDPPacket(port *Port) {
+	if port.Status != "UP" {
+		return
+	}
+	if err := c.sendPacketOut(buildLLDPFrame(c.IngressDeviceID, port), port.Number); err != nil {
+		log.Warnf("Unable to emit LLDP on port %d: %+v", port.Number, err)
+		return
+	}
+	c.RecordLLDPTx(port.Number)
+}
+
+// buildLLDPFrame encodes an outbound LLDP frame advertising this agent's identity on the given
+// port: chassis ID (our device ID), port ID (the port number), TTL and an End TLV.
+func buildLLDPFrame(deviceID string, port *Port) []byte {
+	frame := make([]byte, 0, 64)
+	frame = append(frame, lldpMulticastAddress...)
+	frame = append(frame, localEthernetAddress...)
+	frame = append(frame, byte(lldpEtherType>>8), byte(lldpEtherType&0xff))
+
+	frame = appendLLDPTLV(frame, lldpTLVTypeChassisID, append([]byte{lldpChassisIDSubtypeLocal}, deviceID...))
+	frame = appendLLDPTLV(frame, lldpTLVTypePortID, append([]byte{lldpPortIDSubtypeLocal}, fmt.Sprintf("%d", port.Number)...))
+	frame = appendLLDPTLV(frame, lldpTLVTypeTTL, []byte{0, 120})
+	frame = appendLLDPTLV(frame, lldpTLVTypeEnd, nil)
+	return frame
+}
+
+func appendLLDPTLV(frame []byte, tlvType int, value []byte) []byte {
+	header := uint16(tlvType)<<9 | uint16(len(value))
+	frame = append(frame, byte(header>>8), byte(header))
+	return append(frame, value...)
+}
+
+func (c *Controller) sendPacketOut(payload []byte, egressPort uint32) error {
+	return c.stream.Send(&p4api.StreamMessageRequest{
+		Update: &p4api.StreamMessageRequest_Packet{
+			Packet: &p4api.PacketOut{
+				Payload:  payload,
+				Metadata: []*p4api.PacketMetadata{{MetadataId: egressPortMetadataID, Value: encodeMetadataPort(egressPort)}},
+			},
+		},
+	})
+}
+
+func encodeMetadataPort(port uint32) []byte {
+	value := make([]byte, 2)
+	binary.BigEndian.PutUint16(value, uint16(port))
+	return value
+}
@@ -0,0 +1,240 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/onosproject/onos-net-lib/pkg/gnmiutils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+)
+
+// peerLink is a link observed by a peer agent, learned by subscribing to that peer's own
+// state/link subtree.
+type peerLink struct {
+	EgressDeviceID string
+	EgressPort     uint32
+	LastUpdate     time.Time
+}
+
+const (
+	meshMinBackoff = 1 * time.Second
+	meshMaxBackoff = 30 * time.Second
+)
+
+// startPeerMesh launches one reconnecting goroutine per configured peer address. Each goroutine
+// subscribes to that peer's state/agent-id and state/link subtrees over the peer's own gNMI
+// server, exactly as any other gNMI client would.
+func (c *Controller) startPeerMesh() {
+	for _, address := range c.config.Peers {
+		go c.runMeshPeer(address)
+	}
+}
+
+func (c *Controller) runMeshPeer(address string) {
+	backoff := meshMinBackoff
+	for c.getState() != Stopped {
+		if err := c.subscribeToPeer(address); err != nil {
+			log.Warnf("Peer mesh connection to %s failed: %+v", address, err)
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > meshMaxBackoff {
+			backoff = meshMaxBackoff
+		}
+	}
+}
+
+func (c *Controller) subscribeToPeer(address string) error {
+	dialOpt, err := clientTLSCredentials(c.config.PeerInsecure, c.config.PeerCertPath, c.config.PeerKeyPath)
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.Dial(address, dialOpt, grpc.WithBlock(), grpc.WithTimeout(meshMaxBackoff))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := gnmi.NewGNMIClient(conn).Subscribe(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	request := &gnmi.SubscribeRequest{Request: &gnmi.SubscribeRequest_Subscribe{
+		Subscribe: &gnmi.SubscriptionList{
+			Mode: gnmi.SubscriptionList_STREAM,
+			Subscription: []*gnmi.Subscription{
+				{Path: gnmiutils.ToPath("state/agent-id"), Mode: gnmi.SubscriptionMode_ON_CHANGE},
+				{Path: gnmiutils.ToPath("state/link"), Mode: gnmi.SubscriptionMode_ON_CHANGE},
+			},
+		},
+	}}
+	if err := stream.Send(request); err != nil {
+		return err
+	}
+
+	log.Infof("Peer mesh connected to %s", address)
+
+	var peerID string
+	defer func() {
+		if peerID != "" {
+			c.clearPeerTable(peerID)
+		}
+	}()
+
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if id, ok := c.handlePeerResponse(peerID, response); ok {
+			peerID = id
+		}
+	}
+}
+
+// handlePeerResponse folds a peer's notification into our view of its link table, returning the
+// peer's agent ID once it has been learned (it is sent once, on connect, by state/agent-id).
+func (c *Controller) handlePeerResponse(peerID string, response *gnmi.SubscribeResponse) (string, bool) {
+	update, ok := response.Response.(*gnmi.SubscribeResponse_Update)
+	if !ok {
+		return peerID, false
+	}
+
+	for _, u := range update.Update.GetUpdate() {
+		if lastElemName(u.Path) == "agent-id" {
+			peerID = u.Val.GetStringVal()
+		}
+	}
+
+	if peerID == "" {
+		return peerID, false
+	}
+
+	c.applyPeerLinkUpdates(peerID, update.Update)
+	for _, path := range update.Update.GetDelete() {
+		if ingressPort, neighbor, ok := linkKeysFromPath(path); ok {
+			c.removePeerLink(peerID, ingressPort, neighbor)
+		}
+	}
+	return peerID, true
+}
+
+// peerLinkKey identifies a single (ingress port, neighbor) entry in a peer's link table.
+type peerLinkKey struct {
+	ingressPort uint32
+	neighbor    string
+}
+
+func (c *Controller) applyPeerLinkUpdates(peerID string, notification *gnmi.Notification) {
+	byKey := map[peerLinkKey]*peerLink{}
+	for _, u := range notification.GetUpdate() {
+		ingressPort, neighbor, ok := linkKeysFromPath(u.Path)
+		if !ok {
+			continue
+		}
+		key := peerLinkKey{ingressPort, neighbor}
+		link := byKey[key]
+		if link == nil {
+			link = &peerLink{}
+			c.peerLock.RLock()
+			if existing, ok := c.peerTables[peerID][ingressPort][neighbor]; ok {
+				*link = *existing
+			}
+			c.peerLock.RUnlock()
+			byKey[key] = link
+		}
+		switch lastElemName(u.Path) {
+		case "egress-port":
+			link.EgressPort = uint32(u.Val.GetIntVal())
+		case "egress-device":
+			link.EgressDeviceID = u.Val.GetStringVal()
+		}
+	}
+	for key, link := range byKey {
+		link.LastUpdate = time.Now()
+		c.setPeerLink(peerID, key.ingressPort, key.neighbor, link)
+	}
+}
+
+func (c *Controller) setPeerLink(peerID string, ingressPort uint32, neighbor string, link *peerLink) {
+	c.peerLock.Lock()
+	defer c.peerLock.Unlock()
+	table, ok := c.peerTables[peerID]
+	if !ok {
+		table = make(map[uint32]map[string]*peerLink)
+		c.peerTables[peerID] = table
+	}
+	neighbors, ok := table[ingressPort]
+	if !ok {
+		neighbors = make(map[string]*peerLink)
+		table[ingressPort] = neighbors
+	}
+	neighbors[neighbor] = link
+}
+
+func (c *Controller) removePeerLink(peerID string, ingressPort uint32, neighbor string) {
+	c.peerLock.Lock()
+	defer c.peerLock.Unlock()
+	delete(c.peerTables[peerID][ingressPort], neighbor)
+}
+
+func (c *Controller) clearPeerTable(peerID string) {
+	c.peerLock.Lock()
+	defer c.peerLock.Unlock()
+	delete(c.peerTables, peerID)
+}
+
+// verifyLinkAgainstMesh cross-checks a link we just observed against the reverse side reported by
+// the corresponding peer, if any peers are configured, and records the result as
+// state/link[port=N][neighbor=D/P]/verified.
+func (c *Controller) verifyLinkAgainstMesh(ingressPort uint32, egressPort uint32, egressDeviceID string) {
+	if len(c.config.Peers) == 0 {
+		return
+	}
+
+	// From the peer's perspective, the neighbor it should be reporting on its egressPort is us.
+	theirNeighborKey := neighborKey(c.IngressDeviceID, ingressPort)
+
+	c.peerLock.RLock()
+	reverse, ok := c.peerTables[egressDeviceID][egressPort][theirNeighborKey]
+	c.peerLock.RUnlock()
+
+	maxAge := time.Duration(c.config.MaxLinkAge) * time.Second
+	verified := ok && reverse.EgressDeviceID == c.IngressDeviceID && reverse.EgressPort == ingressPort &&
+		time.Since(reverse.LastUpdate) <= maxAge
+
+	c.addLinkVerifiedToTree(ingressPort, neighborKey(egressDeviceID, egressPort), verified)
+}
+
+func linkKeysFromPath(path *gnmi.Path) (uint32, string, bool) {
+	for _, elem := range path.Elem {
+		if elem.Name == "link" {
+			port, err := strconv.ParseUint(elem.Key["port"], 10, 32)
+			if err != nil {
+				return 0, "", false
+			}
+			return uint32(port), elem.Key["neighbor"], true
+		}
+	}
+	return 0, "", false
+}
+
+func lastElemName(path *gnmi.Path) string {
+	if len(path.Elem) == 0 {
+		return ""
+	}
+	return path.Elem[len(path.Elem)-1].Name
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// agentMetrics holds the Prometheus counters exposed by the diagnostic server. One instance is
+// created per Controller; agent identity is carried as a const label so that multiple agents can
+// share a scrape target without colliding series names.
+type agentMetrics struct {
+	linksAdded   prometheus.Counter
+	linksRemoved prometheus.Counter
+	linksPruned  prometheus.Counter
+
+	hostsAdded   prometheus.Counter
+	hostsRemoved prometheus.Counter
+	hostsPruned  prometheus.Counter
+
+	packetInDecodeErrors       prometheus.Counter
+	pipelineValidationFailures prometheus.Counter
+}
+
+func newAgentMetrics(agentID string) *agentMetrics {
+	labels := prometheus.Labels{"agent": agentID}
+	return &agentMetrics{
+		linksAdded: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "discovery_agent_links_added_total", Help: "Number of links added", ConstLabels: labels}),
+		linksRemoved: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "discovery_agent_links_removed_total", Help: "Number of links removed", ConstLabels: labels}),
+		linksPruned: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "discovery_agent_links_pruned_total", Help: "Number of links pruned for staleness", ConstLabels: labels}),
+
+		hostsAdded: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "discovery_agent_hosts_added_total", Help: "Number of hosts added", ConstLabels: labels}),
+		hostsRemoved: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "discovery_agent_hosts_removed_total", Help: "Number of hosts removed", ConstLabels: labels}),
+		hostsPruned: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "discovery_agent_hosts_pruned_total", Help: "Number of hosts pruned for staleness", ConstLabels: labels}),
+
+		packetInDecodeErrors: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "discovery_agent_packet_in_decode_errors_total", Help: "Number of packet-in messages that failed to decode", ConstLabels: labels}),
+		pipelineValidationFailures: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "discovery_agent_pipeline_validation_failures_total", Help: "Number of pipeline validation failures", ConstLabels: labels}),
+	}
+}
+
+// RecordPacketInDecodeError increments the packet-in decode error counter. It is called from the
+// packet-in handler whenever a received packet cannot be parsed as LLDP or ARP.
+func (c *Controller) RecordPacketInDecodeError() {
+	c.metrics.packetInDecodeErrors.Inc()
+}
+
+// RecordPipelineValidationFailure increments the pipeline validation failure counter. It is
+// called whenever validatePipelineConfiguration cannot fetch the target's pipeline cookie, or
+// finds that the cookie differs from the one the agent expects.
+func (c *Controller) RecordPipelineValidationFailure() {
+	c.metrics.pipelineValidationFailures.Inc()
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"encoding/binary"
+
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// ingressPortMetadataID is the PacketMetadata.MetadataId Stratum uses to carry the ingress port
+// number alongside a PacketIn payload.
+const ingressPortMetadataID = 1
+
+// handlePackets reads PacketIn messages off the P4Runtime stream until the stream fails, decoding
+// LLDP frames into link updates and ARP frames into host updates. It returns on the first receive
+// error and does not re-open the stream.
+func (c *Controller) handlePackets() {
+	c.NoteStreamEstablished()
+	for {
+		response, err := c.stream.Recv()
+		if err != nil {
+			log.Warnf("Packet-in stream failed: %+v", err)
+			return
+		}
+		packetIn := response.GetPacket()
+		if packetIn == nil {
+			continue
+		}
+		c.handlePacketIn(packetIn.GetPayload(), packetIn.GetMetadata())
+	}
+}
+
+func (c *Controller) handlePacketIn(payload []byte, metadata []*p4api.PacketMetadata) {
+	if len(payload) < 14 {
+		c.RecordPacketInDecodeError()
+		return
+	}
+
+	switch etherType := binary.BigEndian.Uint16(payload[12:14]); etherType {
+	case lldpEtherType:
+		c.handleLLDPPacketIn(payload, metadata)
+	case arpEtherType:
+		c.handleARPPacketIn(payload, metadata)
+	}
+}
+
+func (c *Controller) handleLLDPPacketIn(payload []byte, metadata []*p4api.PacketMetadata) {
+	ingressPort, ok := ingressPortFromMetadata(metadata)
+	if !ok {
+		c.RecordPacketInDecodeError()
+		return
+	}
+
+	info, err := decodeLLDPNeighborInfo(payload)
+	if err != nil {
+		log.Warnf("Unable to decode LLDP packet-in on port %d: %+v", ingressPort, err)
+		c.RecordPacketInDecodeError()
+		return
+	}
+
+	c.updateIngressLink(ingressPort, info)
+	c.RecordLLDPRx(ingressPort)
+}
+
+func (c *Controller) handleARPPacketIn(payload []byte, metadata []*p4api.PacketMetadata) {
+	ingressPort, ok := ingressPortFromMetadata(metadata)
+	if !ok {
+		c.RecordPacketInDecodeError()
+		return
+	}
+
+	macString, ipString, err := decodeARPSender(payload)
+	if err != nil {
+		log.Warnf("Unable to decode ARP packet-in on port %d: %+v", ingressPort, err)
+		c.RecordPacketInDecodeError()
+		return
+	}
+
+	c.updateHost(macString, ipString, ingressPort)
+	c.RecordARP(macString)
+}
+
+// ingressPortFromMetadata extracts the ingress port number Stratum attaches to every PacketIn.
+func ingressPortFromMetadata(metadata []*p4api.PacketMetadata) (uint32, bool) {
+	for _, m := range metadata {
+		if m.GetMetadataId() == ingressPortMetadataID {
+			return decodeMetadataPort(m.GetValue())
+		}
+	}
+	return 0, false
+}
+
+func decodeMetadataPort(value []byte) (uint32, bool) {
+	switch len(value) {
+	case 1:
+		return uint32(value[0]), true
+	case 2:
+		return uint32(binary.BigEndian.Uint16(value)), true
+	case 4:
+		return binary.BigEndian.Uint32(value), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// validatePipelineConfiguration re-reads the pipeline configuration cookie the target currently
+// has installed and compares it against the one this agent set during mastership arbitration,
+// catching an out-of-band reconfiguration (e.g. another controller pushing a different P4Info)
+// while we were running.
+func (c *Controller) validatePipelineConfiguration() {
+	response, err := c.p4Client.GetForwardingPipelineConfig(c.ctx, &p4api.GetForwardingPipelineConfigRequest{
+		DeviceId:     c.chassisID,
+		ResponseType: p4api.GetForwardingPipelineConfigRequest_COOKIE_ONLY,
+	})
+	if err != nil {
+		log.Warnf("Unable to validate pipeline configuration: %+v", err)
+		c.RecordPipelineValidationFailure()
+		return
+	}
+	if response.GetConfig().GetCookie().GetCookie() != c.cookie {
+		log.Warnf("Pipeline configuration cookie mismatch; device may have been reconfigured out-of-band")
+		c.RecordPipelineValidationFailure()
+	}
+}
@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-net-lib/pkg/gnmiutils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+const (
+	operStatusPath = "/interfaces/interface[name=*]/state/oper-status"
+	lastChangePath = "/interfaces/interface[name=*]/state/last-change"
+
+	portMonitorRetryDelay = 5 * time.Second
+)
+
+// portMonitor tracks whether the Stratum target has accepted our event-driven, ON_CHANGE
+// subscription for interface state. While active, enterDiscovery skips its fixed
+// PortRediscoveryFrequency poll in favor of the immediate updates this subscription delivers;
+// once it drops, the controller falls back to polling until it can be re-established.
+type portMonitor struct {
+	lock   sync.RWMutex
+	active bool
+}
+
+func (m *portMonitor) setActive(active bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.active = active
+}
+
+func (m *portMonitor) supportsOnChange() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.active
+}
+
+// startPortMonitor launches the long-lived port-change subscription goroutine. It keeps
+// re-subscribing, with a short delay between attempts, for as long as the controller runs.
+func (c *Controller) startPortMonitor() {
+	go c.runPortMonitor()
+}
+
+func (c *Controller) runPortMonitor() {
+	for c.getState() != Stopped {
+		if err := c.subscribePortChanges(); err != nil {
+			log.Warnf("Port-change subscription unavailable; falling back to polling: %+v", err)
+		}
+		c.monitor.setActive(false)
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(portMonitorRetryDelay):
+		}
+	}
+}
+
+// subscribePortChanges opens a STREAM/ON_CHANGE gNMI subscription against the Stratum target for
+// interface operational status and last-change, and feeds the responses to c.ports until the
+// stream fails or the agent is stopped.
+func (c *Controller) subscribePortChanges() error {
+	stream, err := c.gnmiClient.Subscribe(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	request := &gnmi.SubscribeRequest{Request: &gnmi.SubscribeRequest_Subscribe{
+		Subscribe: &gnmi.SubscriptionList{
+			Mode: gnmi.SubscriptionList_STREAM,
+			Subscription: []*gnmi.Subscription{
+				{Path: gnmiutils.ToPath(operStatusPath), Mode: gnmi.SubscriptionMode_ON_CHANGE},
+				{Path: gnmiutils.ToPath(lastChangePath), Mode: gnmi.SubscriptionMode_ON_CHANGE},
+			},
+		},
+	}}
+	if err := stream.Send(request); err != nil {
+		return err
+	}
+
+	c.monitor.setActive(true)
+	log.Infof("Port-change subscription established; switching to event-driven port monitoring")
+
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		c.handlePortChangeResponse(response)
+	}
+}
+
+func (c *Controller) handlePortChangeResponse(response *gnmi.SubscribeResponse) {
+	update, ok := response.Response.(*gnmi.SubscribeResponse_Update)
+	if !ok {
+		return
+	}
+	for _, u := range update.Update.GetUpdate() {
+		name := interfaceNameFromPath(u.Path)
+		if name == "" || len(u.Path.Elem) == 0 {
+			continue
+		}
+		switch u.Path.Elem[len(u.Path.Elem)-1].Name {
+		case "oper-status":
+			c.updatePortStatus(name, u.Val.GetStringVal())
+		case "last-change":
+			c.updatePortLastChange(name, u.Val.GetUintVal())
+		}
+	}
+}
+
+func interfaceNameFromPath(path *gnmi.Path) string {
+	for _, elem := range path.Elem {
+		if elem.Name == "interface" {
+			return elem.Key["name"]
+		}
+	}
+	return ""
+}
+
+// updatePortStatus records the new operational status for a port and, on a flap, immediately
+// re-emits LLDP (port came up) or prunes its link (port went down) instead of waiting for the
+// next PortRediscoveryFrequency/LinkPruneFrequency tick.
+func (c *Controller) updatePortStatus(name string, status string) {
+	c.lock.Lock()
+	port, ok := c.ports[name]
+	if !ok {
+		c.lock.Unlock()
+		return
+	}
+	wasUp := port.Status == "UP"
+	port.Status = status
+	isUp := status == "UP"
+	c.lock.Unlock()
+
+	if wasUp && !isUp {
+		log.Infof("Port %s went down; pruning its link immediately", name)
+		c.pruneLinkForPort(port.Number)
+	} else if !wasUp && isUp {
+		log.Infof("Port %s came up; triggering immediate LLDP emission", name)
+		c.emitLLDPPackets()
+	}
+}
+
+func (c *Controller) updatePortLastChange(name string, lastChange uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if port, ok := c.ports[name]; ok {
+		port.LastChange = lastChange
+	}
+}
+
+func (c *Controller) pruneLinkForPort(portNumber uint32) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for key := range c.links[portNumber] {
+		c.deleteLink(portNumber, key)
+	}
+}